@@ -0,0 +1,60 @@
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+package chacha20
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// EncryptN encrypts the plaintexts with ChaCha20 using the given key
+// and the nonce with the same index. The messages are processed concurrently
+// by up to runtime.GOMAXPROCS goroutines. The i-th ciphertext of the returned
+// slice belongs to the i-th plaintext. Every key-nonce combination must be
+// unique for all time. Decryption is done by passing the ciphertexts
+// as plaintexts. The number of nonces and plaintexts must be equal.
+func EncryptN(key *[32]byte, nonces [][NonceSize]byte, plaintexts [][]byte) ([][]byte, error) {
+	return EncryptNWithContext(context.Background(), key, nonces, plaintexts)
+}
+
+// EncryptNWithContext works like EncryptN but stops processing the
+// messages as soon as the context is done. In this case the context's
+// error is returned.
+func EncryptNWithContext(ctx context.Context, key *[32]byte, nonces [][NonceSize]byte, plaintexts [][]byte) ([][]byte, error) {
+	if len(nonces) != len(plaintexts) {
+		return nil, errors.New("number of nonces and plaintexts must be equal")
+	}
+	n := len(plaintexts)
+	ciphertexts := make([][]byte, n)
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < n; i += workers {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+				ciphertexts[i] = make([]byte, len(plaintexts[i]))
+				NewCipher(&nonces[i], key).XORKeyStream(ciphertexts[i], plaintexts[i])
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return ciphertexts, nil
+}
@@ -0,0 +1,96 @@
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+package chacha20
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestEncryptN(t *testing.T) {
+	var key [32]byte
+	for i := range key {
+		key[i] = byte(i)
+	}
+	nonces := make([][NonceSize]byte, 100)
+	plaintexts := make([][]byte, len(nonces))
+	for i := range nonces {
+		nonces[i][0] = byte(i)
+		plaintexts[i] = bytes.Repeat([]byte{byte(i)}, i)
+	}
+
+	ciphertexts, err := EncryptN(&key, nonces, plaintexts)
+	if err != nil {
+		t.Fatalf("EncryptN failed: %s", err)
+	}
+	if len(ciphertexts) != len(plaintexts) {
+		t.Fatalf("Expected %d ciphertexts but EncryptN returned %d", len(plaintexts), len(ciphertexts))
+	}
+	for i := range plaintexts {
+		expected := make([]byte, len(plaintexts[i]))
+		XORKeyStream(expected, plaintexts[i], &nonces[i], &key, 0)
+		if !bytes.Equal(ciphertexts[i], expected) {
+			t.Fatalf("Message %d: Encryption failed:\nFound   : %x\nExpected: %x", i, ciphertexts[i], expected)
+		}
+	}
+
+	decrypted, err := EncryptN(&key, nonces, ciphertexts)
+	if err != nil {
+		t.Fatalf("EncryptN failed: %s", err)
+	}
+	for i := range plaintexts {
+		if !bytes.Equal(decrypted[i], plaintexts[i]) {
+			t.Fatalf("Message %d: Decryption failed:\nFound   : %x\nExpected: %x", i, decrypted[i], plaintexts[i])
+		}
+	}
+
+	if _, err = EncryptN(&key, nonces[:1], plaintexts); err == nil {
+		t.Fatal("EncryptN accepted different number of nonces and plaintexts")
+	}
+}
+
+func TestEncryptNWithContext(t *testing.T) {
+	var key [32]byte
+	nonces := make([][NonceSize]byte, 16)
+	plaintexts := make([][]byte, len(nonces))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := EncryptNWithContext(ctx, &key, nonces, plaintexts); err != context.Canceled {
+		t.Fatalf("Expected %v but EncryptNWithContext returned %v", context.Canceled, err)
+	}
+}
+
+func BenchmarkEncryptN1000x16B(b *testing.B) {
+	var key [32]byte
+	nonces := make([][NonceSize]byte, 1000)
+	plaintexts := make([][]byte, len(nonces))
+	for i := range plaintexts {
+		plaintexts[i] = make([]byte, 16)
+	}
+	b.SetBytes(1000 * 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		EncryptN(&key, nonces, plaintexts)
+	}
+}
+
+func BenchmarkSequential1000x16B(b *testing.B) {
+	var key [32]byte
+	nonces := make([][NonceSize]byte, 1000)
+	plaintexts := make([][]byte, len(nonces))
+	for i := range plaintexts {
+		plaintexts[i] = make([]byte, 16)
+	}
+	b.SetBytes(1000 * 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ciphertexts := make([][]byte, len(plaintexts))
+		for j := range plaintexts {
+			ciphertexts[j] = make([]byte, len(plaintexts[j]))
+			NewCipher(&nonces[j], &key).XORKeyStream(ciphertexts[j], plaintexts[j])
+		}
+	}
+}
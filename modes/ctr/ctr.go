@@ -0,0 +1,105 @@
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+// Package ctr implements a multi-channel (spliced) CTR mode
+// for block ciphers.
+//
+// Every channel is encrypted with its own CTR stream. The IV of
+// a channel is derived from a master IV and the channel index
+// using HKDF-SHA256. So every channel can be en/decrypted
+// independently - without access to the data of other channels.
+// Notice that one specific key-masterIV combination must be unique
+// for all time.
+package ctr
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// ChannelCTR is the CTR stream of one channel.
+// It implements the cipher.Stream interface.
+type ChannelCTR struct {
+	block  cipher.Block
+	iv     []byte
+	stream cipher.Stream
+}
+
+// NewMultiChannel returns numChannels independent CTR streams wrapping
+// the cipher.Block. The IV of the i-th channel is derived from the masterIV
+// and the channel index i. The length of the masterIV must be equal to the
+// block size of the cipher and numChannels must be greater than 0.
+func NewMultiChannel(b cipher.Block, masterIV []byte, numChannels int) ([]*ChannelCTR, error) {
+	if b == nil {
+		return nil, errors.New("the cipher.Block must not be nil")
+	}
+	if len(masterIV) != b.BlockSize() {
+		return nil, errors.New("master IV length must be equal to the block size of the cipher")
+	}
+	if numChannels < 1 {
+		return nil, errors.New("number of channels must be greater than 0")
+	}
+
+	streams := make([]*ChannelCTR, numChannels)
+	for i := range streams {
+		iv, err := deriveIV(masterIV, uint64(i))
+		if err != nil {
+			return nil, err
+		}
+		streams[i] = &ChannelCTR{
+			block:  b,
+			iv:     iv,
+			stream: cipher.NewCTR(b, iv),
+		}
+	}
+	return streams, nil
+}
+
+// XORKeyStream XORs each byte in the given slice with a byte from the
+// channel's key stream. Dst and src may be the same slice but otherwise
+// should not overlap. If len(dst) < len(src) this function panics.
+func (c *ChannelCTR) XORKeyStream(dst, src []byte) {
+	c.stream.XORKeyStream(dst, src)
+}
+
+// DecryptChannel decrypts the ciphertext of the channel with the index
+// channelIdx. The ciphertext must start at the beginning of the channel's
+// key stream. The state of the channel's stream is not modified, so
+// DecryptChannel can be used independently of previous XORKeyStream calls.
+func DecryptChannel(streams []*ChannelCTR, channelIdx int, ciphertext []byte) ([]byte, error) {
+	if channelIdx < 0 || channelIdx >= len(streams) {
+		return nil, errors.New("channel index out of range")
+	}
+	c := streams[channelIdx]
+	if c == nil {
+		return nil, errors.New("the channel stream must not be nil")
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCTR(c.block, c.iv).XORKeyStream(plaintext, ciphertext)
+	return plaintext, nil
+}
+
+// deriveIV derives the IV of a channel from the masterIV
+// and the channel index using HKDF-SHA256.
+func deriveIV(masterIV []byte, channel uint64) ([]byte, error) {
+	var info [8]byte
+	info[0] = byte(channel >> 56)
+	info[1] = byte(channel >> 48)
+	info[2] = byte(channel >> 40)
+	info[3] = byte(channel >> 32)
+	info[4] = byte(channel >> 24)
+	info[5] = byte(channel >> 16)
+	info[6] = byte(channel >> 8)
+	info[7] = byte(channel)
+
+	iv := make([]byte, len(masterIV))
+	if _, err := io.ReadFull(hkdf.New(sha256.New, masterIV, nil, info[:]), iv); err != nil {
+		return nil, err
+	}
+	return iv, nil
+}
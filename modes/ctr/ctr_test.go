@@ -0,0 +1,113 @@
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+package ctr
+
+import (
+	"bytes"
+	"crypto/aes"
+	"testing"
+)
+
+func TestNewMultiChannel(t *testing.T) {
+	block, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		t.Fatalf("Failed to create AES-128 instance: %s", err)
+	}
+	iv := make([]byte, aes.BlockSize)
+
+	if _, err = NewMultiChannel(nil, iv, 1); err == nil {
+		t.Fatal("NewMultiChannel accepted nil cipher.Block")
+	}
+	if _, err = NewMultiChannel(block, iv[:aes.BlockSize-1], 1); err == nil {
+		t.Fatalf("NewMultiChannel accepted invalid master IV length: %d", aes.BlockSize-1)
+	}
+	if _, err = NewMultiChannel(block, iv, 0); err == nil {
+		t.Fatalf("NewMultiChannel accepted invalid number of channels: %d", 0)
+	}
+
+	streams, err := NewMultiChannel(block, iv, 4)
+	if err != nil {
+		t.Fatalf("Failed to create multi-channel CTR: %s", err)
+	}
+	if len(streams) != 4 {
+		t.Fatalf("Expected %d streams but NewMultiChannel returned %d", 4, len(streams))
+	}
+	for i := range streams {
+		for j := i + 1; j < len(streams); j++ {
+			if bytes.Equal(streams[i].iv, streams[j].iv) {
+				t.Fatalf("Channel %d and %d use the same IV", i, j)
+			}
+		}
+	}
+}
+
+func TestDecryptChannel(t *testing.T) {
+	block, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		t.Fatalf("Failed to create AES-128 instance: %s", err)
+	}
+	iv := make([]byte, aes.BlockSize)
+
+	streams, err := NewMultiChannel(block, iv, 3)
+	if err != nil {
+		t.Fatalf("Failed to create multi-channel CTR: %s", err)
+	}
+
+	msgs := make([][]byte, len(streams))
+	ciphertexts := make([][]byte, len(streams))
+	for i, s := range streams {
+		msgs[i] = bytes.Repeat([]byte{byte(i)}, 100+i)
+		ciphertexts[i] = make([]byte, len(msgs[i]))
+		// encrypt in two parts to check the stream state
+		s.XORKeyStream(ciphertexts[i][:33], msgs[i][:33])
+		s.XORKeyStream(ciphertexts[i][33:], msgs[i][33:])
+	}
+
+	for i := range streams {
+		// decrypt with freshly derived streams - other channels are unknown
+		fresh, err := NewMultiChannel(block, iv, len(streams))
+		if err != nil {
+			t.Fatalf("Failed to create multi-channel CTR: %s", err)
+		}
+		plaintext, err := DecryptChannel(fresh, i, ciphertexts[i])
+		if err != nil {
+			t.Fatalf("Channel %d: DecryptChannel failed: %s", i, err)
+		}
+		if !bytes.Equal(plaintext, msgs[i]) {
+			t.Fatalf("Channel %d: Decryption failed:\nFound   : %x\nExpected: %x", i, plaintext, msgs[i])
+		}
+
+		// the already used streams must produce the same result
+		plaintext, err = DecryptChannel(streams, i, ciphertexts[i])
+		if err != nil {
+			t.Fatalf("Channel %d: DecryptChannel failed: %s", i, err)
+		}
+		if !bytes.Equal(plaintext, msgs[i]) {
+			t.Fatalf("Channel %d: Decryption failed:\nFound   : %x\nExpected: %x", i, plaintext, msgs[i])
+		}
+	}
+
+	if _, err = DecryptChannel(streams, -1, ciphertexts[0]); err == nil {
+		t.Fatalf("DecryptChannel accepted invalid channel index: %d", -1)
+	}
+	if _, err = DecryptChannel(streams, len(streams), ciphertexts[0]); err == nil {
+		t.Fatalf("DecryptChannel accepted invalid channel index: %d", len(streams))
+	}
+}
+
+func BenchmarkChannel1K(b *testing.B) {
+	block, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		b.Fatalf("Failed to create AES-128 instance: %s", err)
+	}
+	streams, err := NewMultiChannel(block, make([]byte, aes.BlockSize), 1)
+	if err != nil {
+		b.Fatalf("Failed to create multi-channel CTR: %s", err)
+	}
+	buf := make([]byte, 1024)
+	b.SetBytes(1024)
+	for i := 0; i < b.N; i++ {
+		streams[0].XORKeyStream(buf, buf)
+	}
+}
@@ -0,0 +1,89 @@
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+// Package gcm implements the packet protection of QUIC
+// described in RFC 9001 (Section 5.3).
+//
+// QUIC derives the nonce of every packet from a base nonce
+// (the IV) and the packet number. The packet number is left-padded
+// to the nonce size and XORed with the base nonce. The header of
+// the packet is used as additional data.
+package gcm
+
+import (
+	"crypto/cipher"
+
+	"github.com/enceve/crypto"
+)
+
+// QUICAead protects QUIC packets using an AEAD cipher
+// and the nonce construction of RFC 9001.
+type QUICAead struct {
+	aead      cipher.AEAD
+	baseNonce []byte
+}
+
+// NewQUIC returns a *QUICAead wrapping the cipher.Block in
+// GCM mode (e.g. AEAD_AES_128_GCM). The block cipher must have
+// a block size of 128 bit and the length of the baseNonce must
+// be equal to the GCM nonce size (12 byte).
+func NewQUIC(b cipher.Block, baseNonce []byte) (*QUICAead, error) {
+	aead, err := cipher.NewGCM(b)
+	if err != nil {
+		return nil, err
+	}
+	return NewQUICWithAEAD(aead, baseNonce)
+}
+
+// NewQUICWithAEAD returns a *QUICAead wrapping the cipher.AEAD
+// (e.g. AEAD_CHACHA20_POLY1305). The length of the baseNonce must be
+// equal to the nonce size of the AEAD and must be at least 8 byte.
+func NewQUICWithAEAD(aead cipher.AEAD, baseNonce []byte) (*QUICAead, error) {
+	if n := len(baseNonce); n != aead.NonceSize() || n < 8 {
+		return nil, crypto.NonceSizeError(n)
+	}
+	c := &QUICAead{
+		aead:      aead,
+		baseNonce: make([]byte, len(baseNonce)),
+	}
+	copy(c.baseNonce, baseNonce)
+	return c, nil
+}
+
+// Overhead returns the maximum difference between the lengths of a
+// plaintext and its ciphertext.
+func (c *QUICAead) Overhead() int { return c.aead.Overhead() }
+
+// SealPacket encrypts and authenticates the plaintext of the packet
+// with the given packet number and authenticates the header. The dst
+// buffer is handled as by the Seal method of the wrapped AEAD.
+// A packet number must not be used twice for one key.
+func (c *QUICAead) SealPacket(dst []byte, packetNum uint64, header, plaintext []byte) []byte {
+	return c.aead.Seal(dst, c.nonce(packetNum), plaintext, header)
+}
+
+// OpenPacket decrypts and authenticates the ciphertext of the packet
+// with the given packet number and authenticates the header. The dst
+// buffer is handled as by the Open method of the wrapped AEAD.
+// If the authentication fails, a non-nil error is returned.
+func (c *QUICAead) OpenPacket(dst []byte, packetNum uint64, header, ciphertext []byte) ([]byte, error) {
+	return c.aead.Open(dst, c.nonce(packetNum), ciphertext, header)
+}
+
+// nonce returns the base nonce XORed with the
+// left-padded (big endian) packet number.
+func (c *QUICAead) nonce(packetNum uint64) []byte {
+	nonce := make([]byte, len(c.baseNonce))
+	copy(nonce, c.baseNonce)
+
+	n := len(nonce)
+	nonce[n-8] ^= byte(packetNum >> 56)
+	nonce[n-7] ^= byte(packetNum >> 48)
+	nonce[n-6] ^= byte(packetNum >> 40)
+	nonce[n-5] ^= byte(packetNum >> 32)
+	nonce[n-4] ^= byte(packetNum >> 24)
+	nonce[n-3] ^= byte(packetNum >> 16)
+	nonce[n-2] ^= byte(packetNum >> 8)
+	nonce[n-1] ^= byte(packetNum)
+	return nonce
+}
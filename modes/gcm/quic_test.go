@@ -0,0 +1,66 @@
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+package gcm
+
+import (
+	"crypto/aes"
+	"testing"
+)
+
+func TestNewQUIC(t *testing.T) {
+	block, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		t.Fatalf("Failed to create AES-128 instance: %s", err)
+	}
+	if _, err = NewQUIC(block, make([]byte, 11)); err == nil {
+		t.Fatalf("NewQUIC accepted invalid base nonce length: %d", 11)
+	}
+	if _, err = NewQUIC(block, make([]byte, 13)); err == nil {
+		t.Fatalf("NewQUIC accepted invalid base nonce length: %d", 13)
+	}
+	if _, err = NewQUIC(block, make([]byte, 12)); err != nil {
+		t.Fatalf("Failed to create QUICAead instance: %s", err)
+	}
+}
+
+func TestOpenPacket(t *testing.T) {
+	block, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		t.Fatalf("Failed to create AES-128 instance: %s", err)
+	}
+	c, err := NewQUIC(block, make([]byte, 12))
+	if err != nil {
+		t.Fatalf("Failed to create QUICAead instance: %s", err)
+	}
+	header, msg := []byte("header"), make([]byte, 64)
+	ciphertext := c.SealPacket(nil, 42, header, msg)
+
+	if _, err = c.OpenPacket(nil, 42, header, ciphertext); err != nil {
+		t.Fatalf("OpenPacket failed: %s", err)
+	}
+	if _, err = c.OpenPacket(nil, 43, header, ciphertext); err == nil {
+		t.Fatal("OpenPacket accepted wrong packet number")
+	}
+	if _, err = c.OpenPacket(nil, 42, header[1:], ciphertext); err == nil {
+		t.Fatal("OpenPacket accepted wrong header")
+	}
+}
+
+func BenchmarkSealPacket1K(b *testing.B) {
+	block, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		b.Fatalf("Failed to create AES-128 instance: %s", err)
+	}
+	c, err := NewQUIC(block, make([]byte, 12))
+	if err != nil {
+		b.Fatalf("Failed to create QUICAead instance: %s", err)
+	}
+	header := make([]byte, 20)
+	msg := make([]byte, 1024)
+	dst := make([]byte, 0, len(msg)+c.Overhead())
+	b.SetBytes(1024)
+	for i := 0; i < b.N; i++ {
+		dst = c.SealPacket(dst[:0], uint64(i), header, msg)
+	}
+}
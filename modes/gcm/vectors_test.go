@@ -0,0 +1,111 @@
+// Use of this source code is governed by a license
+// that can be found in the LICENSE file.
+
+package gcm
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/enceve/crypto/chacha20"
+)
+
+func fromHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Test vectors from RFC 9001 Appendix A.
+// The ciphertext is the protected payload of the packet
+// and the header is the unprotected packet header.
+var aesVectors = []struct {
+	key, iv, header, plaintext, ciphertext string
+	packetNum                              uint64
+}{
+	// A.3 Server Initial
+	{
+		key:       "cf3a5331653c364c88f0f379b6067e37",
+		iv:        "0ac1493ca1905853b0bba03e",
+		header:    "c1000000010008f067a5502a4262b50040750001",
+		packetNum: 1,
+		plaintext: "02000000000600405a020000560303eefce7f7b37ba1d1632e96677825ddf7398" +
+			"8cfc79825df566dc5430b9a045a1200130100002e00330024001d00209d3c940d" +
+			"89690b84d08a60993c144eca684d1081287c834d5311bcf32bb9da1a002b00020304",
+		ciphertext: "5a482cd0991cd25b0aac406a5816b6394100f37a1c69797554780bb38cc5" +
+			"a99f5ede4cf73c3ec2493a1839b3dbcba3f6ea46c5b7684df3548e7ddeb9c3bf" +
+			"9c73cc3f3bded74b562bfb19fb84022f8ef4cdd93795d77d06edbb7aaf2f5889" +
+			"1850abbdca3d20398c276456cbc42158407dd074ee",
+	},
+}
+
+var chachaVectors = []struct {
+	key, iv, header, plaintext, ciphertext string
+	packetNum                              uint64
+}{
+	// A.5 ChaCha20-Poly1305 Short Header Packet
+	{
+		key:        "c6d98ff3441c3fe1b2182094f69caa2ed4b716b65488960a7a984979fb23e1c8",
+		iv:         "e0459b3474bdd0e44a41c144",
+		header:     "4200bff4",
+		packetNum:  654360564,
+		plaintext:  "01",
+		ciphertext: "655e5cd55c41f69080575d7999c25a5bfb",
+	},
+}
+
+func TestAESVectors(t *testing.T) {
+	for i, v := range aesVectors {
+		block, err := aes.NewCipher(fromHex(v.key))
+		if err != nil {
+			t.Fatalf("Test vector %d: Failed to create AES instance: %s", i, err)
+		}
+		c, err := NewQUIC(block, fromHex(v.iv))
+		if err != nil {
+			t.Fatalf("Test vector %d: Failed to create QUICAead instance: %s", i, err)
+		}
+		header, plaintext, ciphertext := fromHex(v.header), fromHex(v.plaintext), fromHex(v.ciphertext)
+
+		sealed := c.SealPacket(nil, v.packetNum, header, plaintext)
+		if !bytes.Equal(sealed, ciphertext) {
+			t.Fatalf("Test vector %d: Seal failed:\nFound   : %x\nExpected: %x", i, sealed, ciphertext)
+		}
+
+		opened, err := c.OpenPacket(nil, v.packetNum, header, ciphertext)
+		if err != nil {
+			t.Fatalf("Test vector %d: Open failed: %s", i, err)
+		}
+		if !bytes.Equal(opened, plaintext) {
+			t.Fatalf("Test vector %d: Open failed:\nFound   : %x\nExpected: %x", i, opened, plaintext)
+		}
+	}
+}
+
+func TestChaCha20Poly1305Vectors(t *testing.T) {
+	for i, v := range chachaVectors {
+		var key [32]byte
+		copy(key[:], fromHex(v.key))
+		c, err := NewQUICWithAEAD(chacha20.NewChaCha20Poly1305(&key), fromHex(v.iv))
+		if err != nil {
+			t.Fatalf("Test vector %d: Failed to create QUICAead instance: %s", i, err)
+		}
+		header, plaintext, ciphertext := fromHex(v.header), fromHex(v.plaintext), fromHex(v.ciphertext)
+
+		sealed := c.SealPacket(make([]byte, len(ciphertext)), v.packetNum, header, plaintext)
+		if !bytes.Equal(sealed, ciphertext) {
+			t.Fatalf("Test vector %d: Seal failed:\nFound   : %x\nExpected: %x", i, sealed, ciphertext)
+		}
+
+		opened, err := c.OpenPacket(make([]byte, len(plaintext)), v.packetNum, header, ciphertext)
+		if err != nil {
+			t.Fatalf("Test vector %d: Open failed: %s", i, err)
+		}
+		if !bytes.Equal(opened, plaintext) {
+			t.Fatalf("Test vector %d: Open failed:\nFound   : %x\nExpected: %x", i, opened, plaintext)
+		}
+	}
+}